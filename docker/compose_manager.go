@@ -0,0 +1,366 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/go-connections/nat"
+	"go.viam.com/rdk/logging"
+)
+
+// composeProjectLabel is the label docker compose itself stamps onto every
+// resource it creates for a project; reusing it lets PS/Down/Events find
+// "our" containers without tracking IDs separately.
+const composeProjectLabel = "com.docker.compose.project"
+const composeServiceLabel = "com.docker.compose.service"
+
+// UpOptions controls how ComposeManager.Up brings a project's services up.
+type UpOptions struct {
+	// Detach starts each container after creating it, without attaching,
+	// like `docker compose up -d`. When it is false, Up only creates the
+	// containers and leaves them stopped.
+	Detach bool
+}
+
+// DownOptions controls how ComposeManager.Down tears a project down.
+type DownOptions struct {
+	RemoveVolumes bool
+}
+
+// ServiceStatus is one row of ComposeManager.PS's output, analogous to
+// `docker compose ps`.
+type ServiceStatus struct {
+	Service     string
+	ContainerID string
+	State       string
+	Status      string
+}
+
+// Event is one lifecycle event (container/network/volume create, start,
+// die, ...) observed for a compose project.
+type Event struct {
+	Type    string
+	Action  string
+	Service string
+	Time    time.Time
+}
+
+// ComposeManager parses compose files with compose-go and runs them natively
+// against the Engine API, rather than shelling out to a `docker compose`
+// binary.
+type ComposeManager struct {
+	logger logging.Logger
+	engine *EngineDockerManager
+}
+
+// NewComposeManager returns a ComposeManager that creates project resources
+// through engine's Docker Engine API client.
+func NewComposeManager(logger logging.Logger, engine *EngineDockerManager) *ComposeManager {
+	return &ComposeManager{logger: logger, engine: engine}
+}
+
+// LoadProject parses and validates the given compose files, resolving
+// ${VAR} interpolation from env. Parse errors (bad indentation, unknown
+// keys, missing services) surface here, at config-validate time, rather
+// than later when a service actually fails to start.
+func (cm *ComposeManager) LoadProject(ctx context.Context, files []string, env map[string]string) (*types.Project, error) {
+	options, err := cli.NewProjectOptions(
+		files,
+		cli.WithEnv(envSlice(env)),
+		cli.WithDotEnv,
+		cli.WithName(projectNameFromFiles(files)),
+		cli.WithResolvedPaths(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compose configuration: %w", err)
+	}
+
+	project, err := options.LoadProject(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compose file: %w", err)
+	}
+
+	return project, nil
+}
+
+// Up creates the project's networks, volumes, and one container per
+// service, starting each container when opts.Detach is set.
+func (cm *ComposeManager) Up(ctx context.Context, project *types.Project, opts UpOptions) error {
+	for name := range project.Networks {
+		networkName := fmt.Sprintf("%s_%s", project.Name, name)
+		if _, err := cm.engine.cli.NetworkCreate(ctx, networkName, network.CreateOptions{
+			Labels: map[string]string{composeProjectLabel: project.Name},
+		}); err != nil {
+			return fmt.Errorf("creating network %s: %w", networkName, err)
+		}
+	}
+
+	for name := range project.Volumes {
+		volumeName := fmt.Sprintf("%s_%s", project.Name, name)
+		if _, err := cm.engine.cli.VolumeCreate(ctx, volume.CreateOptions{
+			Name:   volumeName,
+			Labels: map[string]string{composeProjectLabel: project.Name},
+		}); err != nil {
+			return fmt.Errorf("creating volume %s: %w", volumeName, err)
+		}
+	}
+
+	for _, service := range project.Services {
+		containerName := fmt.Sprintf("%s_%s", project.Name, service.Name)
+
+		// ContainerCreate fails with "No such image" if the service's image
+		// hasn't been pulled yet, so make sure it's present first.
+		reader, err := cm.engine.cli.ImagePull(ctx, service.Image, image.PullOptions{})
+		if err != nil {
+			return fmt.Errorf("pulling image %s for service %s: %w", service.Image, service.Name, err)
+		}
+		// ImagePull only errors on a failed *request* - an actual pull
+		// failure (bad digest, "manifest unknown", auth denied) arrives as
+		// a message in the stream body itself, so it must be decoded
+		// rather than discarded.
+		err = scanPullStream(reader, nil)
+		reader.Close()
+		if err != nil {
+			return fmt.Errorf("pulling image %s for service %s: %w", service.Image, service.Name, err)
+		}
+
+		exposedPorts, portBindings, err := servicePortBindings(service.Ports)
+		if err != nil {
+			return fmt.Errorf("translating ports for service %s: %w", service.Name, err)
+		}
+
+		resp, err := cm.engine.cli.ContainerCreate(ctx,
+			&container.Config{
+				Image:        service.Image,
+				Cmd:          service.Command,
+				WorkingDir:   service.WorkingDir,
+				Env:          envSlice(service.Environment.Resolve(func(s string) (string, bool) { return "", false })),
+				ExposedPorts: exposedPorts,
+				Labels: map[string]string{
+					composeProjectLabel: project.Name,
+					composeServiceLabel: service.Name,
+				},
+			},
+			&container.HostConfig{
+				PortBindings: portBindings,
+				Mounts:       serviceMounts(project.Name, service.Volumes),
+			},
+			serviceNetworkingConfig(project.Name, service.Networks),
+			nil,
+			containerName,
+		)
+		if err != nil {
+			return fmt.Errorf("creating service %s: %w", service.Name, err)
+		}
+
+		if opts.Detach {
+			if err := cm.engine.cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+				return fmt.Errorf("starting service %s: %w", service.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// servicePortBindings translates a service's `ports:` entries into the
+// ExposedPorts/PortBindings shapes ContainerCreate expects.
+func servicePortBindings(ports []types.ServicePortConfig) (nat.PortSet, nat.PortMap, error) {
+	exposedPorts := make(nat.PortSet)
+	portBindings := make(nat.PortMap)
+
+	for _, p := range ports {
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+
+		containerPort, err := nat.NewPort(protocol, strconv.Itoa(int(p.Target)))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		exposedPorts[containerPort] = struct{}{}
+		if p.Published != "" {
+			portBindings[containerPort] = append(portBindings[containerPort], nat.PortBinding{
+				HostIP:   p.HostIP,
+				HostPort: p.Published,
+			})
+		}
+	}
+
+	return exposedPorts, portBindings, nil
+}
+
+// serviceMounts translates a service's `volumes:` entries into Docker
+// mounts, resolving named (project) volumes to their `<project>_<name>`
+// form and passing bind mounts through as-is.
+func serviceMounts(projectName string, volumes []types.ServiceVolumeConfig) []mount.Mount {
+	var mounts []mount.Mount
+	for _, v := range volumes {
+		m := mount.Mount{
+			Target:   v.Target,
+			ReadOnly: v.ReadOnly,
+		}
+
+		switch v.Type {
+		case types.VolumeTypeBind:
+			m.Type = mount.TypeBind
+			m.Source = v.Source
+		default:
+			m.Type = mount.TypeVolume
+			m.Source = fmt.Sprintf("%s_%s", projectName, v.Source)
+		}
+
+		mounts = append(mounts, m)
+	}
+	return mounts
+}
+
+// serviceNetworkingConfig joins a service to the `<project>_<name>`
+// networks Up already created for it.
+func serviceNetworkingConfig(projectName string, networks map[string]*types.ServiceNetworkConfig) *network.NetworkingConfig {
+	if len(networks) == 0 {
+		return nil
+	}
+
+	endpoints := make(map[string]*network.EndpointSettings, len(networks))
+	for name := range networks {
+		endpoints[fmt.Sprintf("%s_%s", projectName, name)] = &network.EndpointSettings{}
+	}
+
+	return &network.NetworkingConfig{EndpointsConfig: endpoints}
+}
+
+// Down stops and removes every container Up created for project, and its
+// networks; volumes are only removed when opts.RemoveVolumes is set.
+func (cm *ComposeManager) Down(ctx context.Context, project *types.Project, opts DownOptions) error {
+	containers, err := cm.engine.cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", composeProjectLabel+"="+project.Name)),
+	})
+	if err != nil {
+		return fmt.Errorf("listing containers for %s: %w", project.Name, err)
+	}
+
+	for _, c := range containers {
+		if err := cm.engine.cli.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true}); err != nil {
+			return fmt.Errorf("removing container %s: %w", c.ID, err)
+		}
+	}
+
+	for name := range project.Networks {
+		networkName := fmt.Sprintf("%s_%s", project.Name, name)
+		if err := cm.engine.cli.NetworkRemove(ctx, networkName); err != nil {
+			cm.logger.Warnf("Failed to remove network %s: %v", networkName, err)
+		}
+	}
+
+	if opts.RemoveVolumes {
+		for name := range project.Volumes {
+			volumeName := fmt.Sprintf("%s_%s", project.Name, name)
+			if err := cm.engine.cli.VolumeRemove(ctx, volumeName, true); err != nil {
+				cm.logger.Warnf("Failed to remove volume %s: %v", volumeName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// PS reports the current state of every container belonging to project,
+// analogous to `docker compose ps`.
+func (cm *ComposeManager) PS(ctx context.Context, project *types.Project) ([]ServiceStatus, error) {
+	containers, err := cm.engine.cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", composeProjectLabel+"="+project.Name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []ServiceStatus
+	for _, c := range containers {
+		statuses = append(statuses, ServiceStatus{
+			Service:     c.Labels[composeServiceLabel],
+			ContainerID: c.ID,
+			State:       c.State,
+			Status:      c.Status,
+		})
+	}
+
+	return statuses, nil
+}
+
+// Events streams lifecycle events (container/network/volume create, start,
+// die, ...) for project's resources until ctx is canceled.
+func (cm *ComposeManager) Events(ctx context.Context, project *types.Project) (<-chan Event, error) {
+	msgs, errs := cm.engine.cli.Events(ctx, events.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", composeProjectLabel+"="+project.Name)),
+	})
+
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				event := Event{
+					Type:    string(msg.Type),
+					Action:  string(msg.Action),
+					Service: msg.Actor.Attributes[composeServiceLabel],
+					Time:    time.Unix(0, msg.TimeNano),
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			case err, ok := <-errs:
+				if ok && err != nil {
+					cm.logger.Warnf("Compose event stream error for %s: %v", project.Name, err)
+				}
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// envSlice flattens a map of environment variables into the KEY=VALUE
+// slice ContainerCreate's Config.Env expects.
+func envSlice(env map[string]string) []string {
+	var out []string
+	for k, v := range env {
+		out = append(out, fmt.Sprintf("%s=%s", k, v))
+	}
+	return out
+}
+
+// projectNameFromFiles derives a stable compose project name from the
+// directory its first compose file lives in, matching the docker compose
+// CLI's own default when no explicit project name is configured.
+func projectNameFromFiles(files []string) string {
+	if len(files) == 0 {
+		return "default"
+	}
+	return cli.NormalizeProjectName(filepath.Base(filepath.Dir(files[0])))
+}
@@ -3,29 +3,48 @@ package docker
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 	"strings"
+	"time"
 
 	"go.viam.com/rdk/logging"
 )
 
 var ErrImageDoesNotExist = errors.New("image does not exist")
 
+// DockerManager's methods all take a context so a caller can bound or
+// cancel a call that hangs waiting on the Docker daemon (e.g. a stuck pull
+// during module reconfiguration).
 type DockerManager interface {
-	ListContainers() ([]DockerContainerDetails, error)
-	ListImages() ([]DockerImageDetails, error)
-	GetImageDetails(imageId string) (DockerImageDetails, error)
-	InspectContainer(containerHash string) (map[string]interface{}, error)
-	GetContainerImageId(containerId string) (string, error)
-	GetContainerImageDigest(containerId string) (string, error)
-	GetContainersRunningImage(imageDigest string) ([]DockerContainerDetails, error)
-	PullImage(name string, repoDigest string) error
-	PullPrivateImage(name string, githubUsername string, pat string) error
-	RemoveImageByImageId(imageId string) error
-	RemoveImageByRepoDigest(repoDigest string) error
+	ListContainers(ctx context.Context) ([]DockerContainerDetails, error)
+	ListImages(ctx context.Context) ([]DockerImageDetails, error)
+	GetImageDetails(ctx context.Context, imageId string) (DockerImageDetails, error)
+	InspectContainer(ctx context.Context, containerHash string) (map[string]interface{}, error)
+	GetContainerImageId(ctx context.Context, containerId string) (string, error)
+	GetContainerImageDigest(ctx context.Context, containerId string) (string, error)
+	GetContainersRunningImage(ctx context.Context, imageDigest string) ([]DockerContainerDetails, error)
+	PullImage(ctx context.Context, name string, repoDigest string) error
+	PullImageWithProgress(ctx context.Context, name string, repoDigest string, onProgress func(PullProgress)) error
+	PullPrivateImage(ctx context.Context, name string, githubUsername string, pat string) error
+	PullImageFromRegistry(ctx context.Context, imageRef string, credentials *CredentialStore) error
+	RemoveImageByImageId(ctx context.Context, imageId string) error
+	RemoveImageByRepoDigest(ctx context.Context, repoDigest string) error
+
+	StartContainer(ctx context.Context, name string) error
+	StopContainer(ctx context.Context, name string, timeout time.Duration) error
+	KillContainer(ctx context.Context, name string, signal string) error
+	RestartContainer(ctx context.Context, name string, timeout time.Duration) error
+	PauseContainer(ctx context.Context, name string) error
+	UnpauseContainer(ctx context.Context, name string) error
+	RemoveContainer(ctx context.Context, name string, opts RemoveOptions) error
+	ContainerLogs(ctx context.Context, name string, opts LogsOptions) (io.ReadCloser, error)
+	ContainerStats(ctx context.Context, name string, stream bool) (<-chan ContainerStats, error)
+	ContainerTop(ctx context.Context, name string) ([]ProcessInfo, error)
 }
 
 type LocalDockerManager struct {
@@ -51,12 +70,34 @@ type DockerContainerDetails struct {
 	Names       string
 }
 
-func NewLocalDockerManager(logger logging.Logger) DockerManager {
-	return &LocalDockerManager{logger: logger}
+// NewLocalDockerManager returns a DockerManager backed by the Docker Engine
+// API (see EngineDockerManager) when a daemon is reachable over
+// /var/run/docker.sock or DOCKER_HOST, falling back to shelling out to the
+// docker CLI (LocalDockerManager) otherwise.
+//
+// NewEngineDockerManager only constructs the client and validates
+// DOCKER_HOST; it doesn't dial the daemon, since the Engine API client
+// negotiates its API version lazily on the first real request. So we Ping
+// it here before committing to the engine backend - otherwise a host with
+// no daemon running would still get an EngineDockerManager, and every
+// subsequent call would fail against a dead socket instead of falling back.
+func NewLocalDockerManager(ctx context.Context, logger logging.Logger) DockerManager {
+	engine, err := NewEngineDockerManager(logger)
+	if err != nil {
+		logger.Warnf("Falling back to docker CLI backend: %v", err)
+		return &LocalDockerManager{logger: logger}
+	}
+
+	if _, err := engine.cli.Ping(ctx); err != nil {
+		logger.Warnf("Falling back to docker CLI backend: %v", err)
+		return &LocalDockerManager{logger: logger}
+	}
+
+	return engine
 }
 
-func (dm *LocalDockerManager) ListImages() ([]DockerImageDetails, error) {
-	proc := exec.Command("docker", "images", "--digests", "--no-trunc")
+func (dm *LocalDockerManager) ListImages(ctx context.Context) ([]DockerImageDetails, error) {
+	proc := exec.CommandContext(ctx, "docker", "images", "--digests", "--no-trunc")
 	outputBytes, err := proc.Output()
 	if err != nil {
 		return nil, err
@@ -103,8 +144,8 @@ func (dm *LocalDockerManager) ListImages() ([]DockerImageDetails, error) {
 	return containers, nil
 }
 
-func (dm *LocalDockerManager) RemoveImageByImageId(imageId string) error {
-	proc := exec.Command("docker", "rmi", imageId)
+func (dm *LocalDockerManager) RemoveImageByImageId(ctx context.Context, imageId string) error {
+	proc := exec.CommandContext(ctx, "docker", "rmi", imageId)
 	_, err := proc.Output()
 	if err != nil {
 		return err
@@ -113,8 +154,8 @@ func (dm *LocalDockerManager) RemoveImageByImageId(imageId string) error {
 	return nil
 }
 
-func (dm *LocalDockerManager) RemoveImageByRepoDigest(repoDigest string) error {
-	images, err := dm.ListImages()
+func (dm *LocalDockerManager) RemoveImageByRepoDigest(ctx context.Context, repoDigest string) error {
+	images, err := dm.ListImages(ctx)
 	if err != nil {
 		return err
 	}
@@ -124,7 +165,7 @@ func (dm *LocalDockerManager) RemoveImageByRepoDigest(repoDigest string) error {
 			imageId = image.ImageID
 		}
 	}
-	proc := exec.Command("docker", "rmi", imageId)
+	proc := exec.CommandContext(ctx, "docker", "rmi", imageId)
 	_, err = proc.Output()
 	if err != nil {
 		return err
@@ -133,8 +174,8 @@ func (dm *LocalDockerManager) RemoveImageByRepoDigest(repoDigest string) error {
 	return nil
 }
 
-func (dm *LocalDockerManager) ListContainers() ([]DockerContainerDetails, error) {
-	proc := exec.Command("docker", "container", "ls", "--all", "--no-trunc")
+func (dm *LocalDockerManager) ListContainers(ctx context.Context) ([]DockerContainerDetails, error) {
+	proc := exec.CommandContext(ctx, "docker", "container", "ls", "--all", "--no-trunc")
 	outputBytes, err := proc.Output()
 	if err != nil {
 		return nil, err
@@ -201,22 +242,12 @@ func (dm *LocalDockerManager) ListContainers() ([]DockerContainerDetails, error)
 	return containers, nil
 }
 
-func (dm *LocalDockerManager) GetImageDetails(imageId string) (DockerImageDetails, error) {
-	images, err := dm.ListImages()
-	if err != nil {
-		return DockerImageDetails{}, err
-	}
-
-	for _, image := range images {
-		if image.ImageID == imageId {
-			return image, nil
-		}
-	}
-	return DockerImageDetails{}, errors.New("image not found")
+func (dm *LocalDockerManager) GetImageDetails(ctx context.Context, imageId string) (DockerImageDetails, error) {
+	return getImageDetails(ctx, dm, imageId)
 }
 
-func (dm *LocalDockerManager) InspectContainer(containerHash string) (map[string]interface{}, error) {
-	proc := exec.Command("docker", "container", "inspect", containerHash)
+func (dm *LocalDockerManager) InspectContainer(ctx context.Context, containerHash string) (map[string]interface{}, error) {
+	proc := exec.CommandContext(ctx, "docker", "container", "inspect", containerHash)
 	outputBytes, err := proc.Output()
 	if err != nil {
 		return nil, err
@@ -232,52 +263,21 @@ func (dm *LocalDockerManager) InspectContainer(containerHash string) (map[string
 	return container[0], nil
 }
 
-func (dm *LocalDockerManager) GetContainerImageId(containerId string) (string, error) {
-	container, err := dm.InspectContainer(containerId)
-	if err != nil {
-		return "", err
-	}
-
-	return container["Image"].(string), nil
+func (dm *LocalDockerManager) GetContainerImageId(ctx context.Context, containerId string) (string, error) {
+	return getContainerImageId(ctx, dm, containerId)
 }
 
-func (dm *LocalDockerManager) GetContainerImageDigest(containerId string) (string, error) {
-	imageId, err := dm.GetContainerImageId(containerId)
-	if err != nil {
-		return "", err
-	}
-
-	image, err := dm.GetImageDetails(imageId)
-	if err != nil {
-		return "", err
-	}
-
-	return image.RepoDigest, nil
+func (dm *LocalDockerManager) GetContainerImageDigest(ctx context.Context, containerId string) (string, error) {
+	return getContainerImageDigest(ctx, dm, containerId)
 }
 
-func (dm *LocalDockerManager) GetContainersRunningImage(repoDigest string) ([]DockerContainerDetails, error) {
-	containers, err := dm.ListContainers()
-	if err != nil {
-		return nil, err
-	}
-
-	var containersRunningImage []DockerContainerDetails
-	for _, container := range containers {
-		digest, err := dm.GetContainerImageDigest(container.ContainerID)
-		if err != nil {
-			continue
-		}
-		if digest == repoDigest {
-			containersRunningImage = append(containersRunningImage, container)
-		}
-	}
-
-	return containersRunningImage, nil
+func (dm *LocalDockerManager) GetContainersRunningImage(ctx context.Context, repoDigest string) ([]DockerContainerDetails, error) {
+	return getContainersRunningImage(ctx, dm, repoDigest)
 }
 
-func (dm *LocalDockerManager) PullImage(name string, repoDigest string) error {
+func (dm *LocalDockerManager) PullImage(ctx context.Context, name string, repoDigest string) error {
 	dm.logger.Debugf("Pulling image %s %s", name, repoDigest)
-	proc := exec.Command("docker", "pull", fmt.Sprintf("%s@%s", name, repoDigest))
+	proc := exec.CommandContext(ctx, "docker", "pull", fmt.Sprintf("%s@%s", name, repoDigest))
 	// TODO: Read output from proc using a pipe
 	// output:=proc.StdoutPipe()
 
@@ -294,11 +294,11 @@ func (dm *LocalDockerManager) PullImage(name string, repoDigest string) error {
 	return nil
 }
 
-func (dm *LocalDockerManager) PullPrivateImage(name string, githubUsername string, pat string) error {
+func (dm *LocalDockerManager) PullPrivateImage(ctx context.Context, name string, githubUsername string, pat string) error {
 	dm.logger.Debugf("Authenticating with GitHub Container Registry")
 
-	// Using exec.Command for login
-	loginCmd := exec.Command("docker", "login", "ghcr.io", "-u", githubUsername, "--password-stdin")
+	// Using exec.CommandContext for login
+	loginCmd := exec.CommandContext(ctx, "docker", "login", "ghcr.io", "-u", githubUsername, "--password-stdin")
 	stdin, err := loginCmd.StdinPipe()
 	if err != nil {
 		dm.logger.Errorf("Failed to create stdin pipe for login command: %v", err)
@@ -342,7 +342,7 @@ func (dm *LocalDockerManager) PullPrivateImage(name string, githubUsername strin
 
 	// Pull command
 	dm.logger.Debugf("Pulling image %s", name)
-	pullCmd := exec.Command("docker", "pull", name)
+	pullCmd := exec.CommandContext(ctx, "docker", "pull", name)
 
 	outputBytes, err := pullCmd.CombinedOutput()
 	if err != nil {
@@ -53,7 +53,7 @@ func TestImageStarts(t *testing.T) {
 
 	// Now make sure it is actually running
 	dm := LocalDockerManager{}
-	containers, err := dm.ListContainers()
+	containers, err := dm.ListContainers(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(containers))
 
@@ -70,7 +70,7 @@ func TestCleanupOldImage(t *testing.T) {
 	assert.NotNil(t, sensor)
 
 	dm := LocalDockerManager{}
-	images, err := dm.ListImages()
+	images, err := dm.ListImages(context.Background())
 	assert.NoError(t, err)
 	if len(images) != 1 {
 		t.Logf("Found %d images, expected 1", len(images))
@@ -92,11 +92,11 @@ func TestCleanupOldImage(t *testing.T) {
 	}
 	sensor.Reconfigure(context.Background(), deps, newConfig)
 
-	images, err = dm.ListImages()
+	images, err = dm.ListImages(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(images))
 	assert.Equal(t, "sha256:c9cf959fd83770dfdefd8fb42cfef0761432af36a764c077aed54bbc5bb25368", images[0].RepoDigest)
 
-	err = dm.RemoveImageByRepoDigest("sha256:c9cf959fd83770dfdefd8fb42cfef0761432af36a764c077aed54bbc5bb25368")
+	err = dm.RemoveImageByRepoDigest(context.Background(), "sha256:c9cf959fd83770dfdefd8fb42cfef0761432af36a764c077aed54bbc5bb25368")
 	assert.NoError(t, err)
 }
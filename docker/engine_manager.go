@@ -0,0 +1,237 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+	"go.viam.com/rdk/logging"
+)
+
+// EngineDockerManager implements DockerManager against the Docker Engine
+// API (github.com/docker/docker/client) instead of shelling out to the
+// docker CLI. It talks to /var/run/docker.sock by default, or DOCKER_HOST
+// when set, and avoids the whitespace/column parsing that makes
+// LocalDockerManager brittle.
+type EngineDockerManager struct {
+	logger logging.Logger
+	cli    *client.Client
+}
+
+// NewEngineDockerManager builds a client for the local Docker daemon using the
+// standard environment-derived options (DOCKER_HOST, DOCKER_CERT_PATH,
+// DOCKER_TLS_VERIFY). It does not connect to the daemon; the API version is
+// negotiated on the first request.
+func NewEngineDockerManager(logger logging.Logger) (*EngineDockerManager, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, err
+	}
+	return &EngineDockerManager{logger: logger, cli: cli}, nil
+}
+
+func (dm *EngineDockerManager) ListImages(ctx context.Context) ([]DockerImageDetails, error) {
+	summaries, err := dm.cli.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var images []DockerImageDetails
+	for _, summary := range summaries {
+		repository, tag := "<none>", "<none>"
+		if len(summary.RepoTags) > 0 && summary.RepoTags[0] != "<none>:<none>" {
+			repository, tag = splitRepoTag(summary.RepoTags[0])
+		}
+
+		repoDigest := "<none>"
+		if len(summary.RepoDigests) > 0 {
+			repoDigest = repoDigestOnly(summary.RepoDigests[0])
+		}
+
+		images = append(images, DockerImageDetails{
+			Repository: repository,
+			Tag:        tag,
+			RepoDigest: repoDigest,
+			ImageID:    summary.ID,
+			Created:    time.Unix(summary.Created, 0).String(),
+			Size:       strconv.FormatInt(summary.Size, 10),
+		})
+	}
+
+	return images, nil
+}
+
+func (dm *EngineDockerManager) RemoveImageByImageId(ctx context.Context, imageId string) error {
+	_, err := dm.cli.ImageRemove(ctx, imageId, image.RemoveOptions{})
+	return err
+}
+
+func (dm *EngineDockerManager) RemoveImageByRepoDigest(ctx context.Context, repoDigest string) error {
+	images, err := dm.ListImages(ctx)
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		if img.RepoDigest == repoDigest {
+			return dm.RemoveImageByImageId(ctx, img.ImageID)
+		}
+	}
+	return ErrImageDoesNotExist
+}
+
+func (dm *EngineDockerManager) ListContainers(ctx context.Context) ([]DockerContainerDetails, error) {
+	containers, err := dm.cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var details []DockerContainerDetails
+	for _, c := range containers {
+		var ports []string
+		for _, p := range c.Ports {
+			if p.PublicPort != 0 {
+				ports = append(ports, fmt.Sprintf("%s:%d->%d/%s", p.IP, p.PublicPort, p.PrivatePort, p.Type))
+			} else {
+				ports = append(ports, fmt.Sprintf("%d/%s", p.PrivatePort, p.Type))
+			}
+		}
+
+		names := make([]string, len(c.Names))
+		for i, name := range c.Names {
+			names[i] = strings.TrimPrefix(name, "/")
+		}
+
+		details = append(details, DockerContainerDetails{
+			ContainerID: c.ID,
+			Image:       c.Image,
+			Command:     c.Command,
+			Created:     time.Unix(c.Created, 0).String(),
+			Status:      c.Status,
+			Ports:       strings.Join(ports, ", "),
+			Names:       strings.Join(names, ","),
+		})
+	}
+
+	return details, nil
+}
+
+func (dm *EngineDockerManager) GetImageDetails(ctx context.Context, imageId string) (DockerImageDetails, error) {
+	return getImageDetails(ctx, dm, imageId)
+}
+
+func (dm *EngineDockerManager) InspectContainer(ctx context.Context, containerHash string) (map[string]interface{}, error) {
+	inspect, err := dm.cli.ContainerInspect(ctx, containerHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Round-trip through JSON so the shape matches what the CLI backend
+	// returns from `docker container inspect` (a generic string-keyed map),
+	// keeping InspectContainer's signature identical across backends.
+	raw, err := json.Marshal(inspect)
+	if err != nil {
+		return nil, err
+	}
+	var asMap map[string]interface{}
+	if err := json.Unmarshal(raw, &asMap); err != nil {
+		return nil, err
+	}
+
+	return asMap, nil
+}
+
+func (dm *EngineDockerManager) GetContainerImageId(ctx context.Context, containerId string) (string, error) {
+	return getContainerImageId(ctx, dm, containerId)
+}
+
+func (dm *EngineDockerManager) GetContainerImageDigest(ctx context.Context, containerId string) (string, error) {
+	return getContainerImageDigest(ctx, dm, containerId)
+}
+
+func (dm *EngineDockerManager) GetContainersRunningImage(ctx context.Context, repoDigest string) ([]DockerContainerDetails, error) {
+	return getContainersRunningImage(ctx, dm, repoDigest)
+}
+
+func (dm *EngineDockerManager) PullImage(ctx context.Context, name string, repoDigest string) error {
+	ref := fmt.Sprintf("%s@%s", name, repoDigest)
+	dm.logger.Debugf("Pulling image %s", ref)
+
+	reader, err := dm.cli.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// ImagePull only errors on a failed *request* - an actual pull failure
+	// (bad digest, "manifest unknown", auth denied) arrives as a message in
+	// the stream body itself, so it must be decoded rather than discarded.
+	if err := scanPullStream(reader, nil); err != nil {
+		return fmt.Errorf("pulling %s: %w", ref, err)
+	}
+
+	dm.logger.Debugf("Pulled %s", ref)
+	return nil
+}
+
+func (dm *EngineDockerManager) PullPrivateImage(ctx context.Context, name string, githubUsername string, pat string) error {
+	authConfig := registry.AuthConfig{
+		ServerAddress: "ghcr.io",
+		Username:      githubUsername,
+		Password:      pat,
+	}
+
+	encodedAuth, err := json.Marshal(authConfig)
+	if err != nil {
+		return err
+	}
+
+	dm.logger.Debugf("Pulling private image %s", name)
+	reader, err := dm.cli.ImagePull(ctx, name, image.PullOptions{
+		RegistryAuth: base64.URLEncoding.EncodeToString(encodedAuth),
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if err := scanPullStream(reader, nil); err != nil {
+		return fmt.Errorf("pulling %s: %w", name, err)
+	}
+
+	dm.logger.Debugf("Pulled %s", name)
+	return nil
+}
+
+// splitRepoTag splits "repo:tag" into its parts. A "@sha256:..." digest
+// suffix (e.g. "ubuntu@sha256:abc...") is stripped first, since otherwise
+// splitting on the last colon would cut the digest in half and treat
+// "sha256" as part of the repository name.
+func splitRepoTag(repoTag string) (string, string) {
+	repoTag, _, _ = strings.Cut(repoTag, "@")
+
+	idx := strings.LastIndex(repoTag, ":")
+	if idx == -1 {
+		return repoTag, ""
+	}
+	return repoTag[:idx], repoTag[idx+1:]
+}
+
+// repoDigestOnly strips the "repo@" prefix from a "repo@sha256:..." RepoDigest
+// entry, matching the bare "sha256:..." shape DockerImageDetails.RepoDigest
+// uses elsewhere (e.g. from `docker images --digests`).
+func repoDigestOnly(repoDigest string) string {
+	if idx := strings.Index(repoDigest, "@"); idx != -1 {
+		return repoDigest[idx+1:]
+	}
+	return repoDigest
+}
+
+var _ DockerManager = (*EngineDockerManager)(nil)
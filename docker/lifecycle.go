@@ -0,0 +1,510 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// RemoveOptions controls how RemoveContainer tears a container down.
+type RemoveOptions struct {
+	// Force removes the container even if it is still running (SIGKILL).
+	Force bool
+	// RemoveVolumes also removes any anonymous volumes associated with the
+	// container.
+	RemoveVolumes bool
+}
+
+// LogsOptions controls what slice of a container's log ContainerLogs
+// returns.
+type LogsOptions struct {
+	// Follow keeps the returned reader open and streams new log lines as
+	// they're written, like `docker logs -f`.
+	Follow bool
+	// Tail limits output to the last N lines, or "all" for the full log.
+	Tail string
+	// Since only returns logs produced after this time, e.g. "10m" or an
+	// RFC3339 timestamp, matching `docker logs --since`.
+	Since string
+}
+
+// ContainerStats is one sample of a container's resource usage, as reported
+// by `docker stats` / the Engine API's stats stream.
+type ContainerStats struct {
+	CPUPercent       float64
+	MemoryUsageBytes int64
+	MemoryLimitBytes int64
+	NetworkRxBytes   int64
+	NetworkTxBytes   int64
+	BlockReadBytes   int64
+	BlockWriteBytes  int64
+}
+
+// ProcessInfo is one row of `docker top`'s process table.
+type ProcessInfo struct {
+	PID     string
+	User    string
+	Command string
+	// Extra holds any columns beyond PID/User/Command that the runtime
+	// reported (e.g. "TIME", "STAT"), keyed by their column title.
+	Extra map[string]string
+}
+
+func (dm *LocalDockerManager) StartContainer(ctx context.Context, name string) error {
+	_, err := exec.CommandContext(ctx, "docker", "start", name).Output()
+	return err
+}
+
+func (dm *LocalDockerManager) StopContainer(ctx context.Context, name string, timeout time.Duration) error {
+	_, err := exec.CommandContext(ctx, "docker", "stop", "-t", strconv.Itoa(int(timeout.Seconds())), name).Output()
+	return err
+}
+
+func (dm *LocalDockerManager) KillContainer(ctx context.Context, name string, signal string) error {
+	_, err := exec.CommandContext(ctx, "docker", "kill", "-s", signal, name).Output()
+	return err
+}
+
+func (dm *LocalDockerManager) RestartContainer(ctx context.Context, name string, timeout time.Duration) error {
+	_, err := exec.CommandContext(ctx, "docker", "restart", "-t", strconv.Itoa(int(timeout.Seconds())), name).Output()
+	return err
+}
+
+func (dm *LocalDockerManager) PauseContainer(ctx context.Context, name string) error {
+	_, err := exec.CommandContext(ctx, "docker", "pause", name).Output()
+	return err
+}
+
+func (dm *LocalDockerManager) UnpauseContainer(ctx context.Context, name string) error {
+	_, err := exec.CommandContext(ctx, "docker", "unpause", name).Output()
+	return err
+}
+
+func (dm *LocalDockerManager) RemoveContainer(ctx context.Context, name string, opts RemoveOptions) error {
+	args := []string{"rm"}
+	if opts.Force {
+		args = append(args, "-f")
+	}
+	if opts.RemoveVolumes {
+		args = append(args, "-v")
+	}
+	args = append(args, name)
+
+	_, err := exec.CommandContext(ctx, "docker", args...).Output()
+	return err
+}
+
+func (dm *LocalDockerManager) ContainerLogs(ctx context.Context, name string, opts LogsOptions) (io.ReadCloser, error) {
+	args := []string{"logs"}
+	if opts.Follow {
+		args = append(args, "-f")
+	}
+	if opts.Tail != "" {
+		args = append(args, "--tail", opts.Tail)
+	}
+	if opts.Since != "" {
+		args = append(args, "--since", opts.Since)
+	}
+	args = append(args, name)
+
+	proc := exec.CommandContext(ctx, "docker", args...)
+	stdout, err := proc.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	proc.Stderr = proc.Stdout
+
+	if err := proc.Start(); err != nil {
+		return nil, err
+	}
+
+	return &procReadCloser{ReadCloser: stdout, proc: proc}, nil
+}
+
+// procReadCloser wraps the stdout pipe of a running *exec.Cmd so Close waits
+// for the process to exit, matching the io.ReadCloser contract callers of
+// ContainerLogs expect from the Engine API backend's response body.
+type procReadCloser struct {
+	io.ReadCloser
+	proc *exec.Cmd
+}
+
+func (p *procReadCloser) Close() error {
+	p.ReadCloser.Close()
+	return p.proc.Wait()
+}
+
+func (dm *LocalDockerManager) ContainerStats(ctx context.Context, name string, stream bool) (<-chan ContainerStats, error) {
+	args := []string{"stats", "--format", "{{json .}}"}
+	if !stream {
+		args = append(args, "--no-stream")
+	}
+	args = append(args, name)
+
+	proc := exec.CommandContext(ctx, "docker", args...)
+	stdout, err := proc.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := proc.Start(); err != nil {
+		return nil, err
+	}
+
+	out := make(chan ContainerStats)
+	go func() {
+		defer close(out)
+		defer proc.Wait()
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			stats, err := parseDockerStatsLine(scanner.Bytes())
+			if err != nil {
+				dm.logger.Warnf("Failed to parse docker stats output: %v", err)
+				continue
+			}
+			select {
+			case out <- stats:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// dockerStatsLine mirrors the fields `docker stats --format '{{json .}}'`
+// emits, which are all pre-formatted human-readable strings (e.g.
+// "12.34%", "10MiB / 2GiB", "1.2kB / 3.4kB").
+type dockerStatsLine struct {
+	CPUPerc string `json:"CPUPerc"`
+	MemUsage string `json:"MemUsage"`
+	NetIO   string `json:"NetIO"`
+	BlockIO string `json:"BlockIO"`
+}
+
+func parseDockerStatsLine(line []byte) (ContainerStats, error) {
+	var raw dockerStatsLine
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return ContainerStats{}, err
+	}
+
+	cpuPercent, _ := strconv.ParseFloat(strings.TrimSuffix(raw.CPUPerc, "%"), 64)
+	memUsage, memLimit := parseSlashPair(raw.MemUsage)
+	rxBytes, txBytes := parseSlashPair(raw.NetIO)
+	readBytes, writeBytes := parseSlashPair(raw.BlockIO)
+
+	return ContainerStats{
+		CPUPercent:       cpuPercent,
+		MemoryUsageBytes: memUsage,
+		MemoryLimitBytes: memLimit,
+		NetworkRxBytes:   rxBytes,
+		NetworkTxBytes:   txBytes,
+		BlockReadBytes:   readBytes,
+		BlockWriteBytes:  writeBytes,
+	}, nil
+}
+
+// parseSlashPair parses docker's "<value><unit> / <value><unit>" stat
+// fields (e.g. "10MiB / 2GiB") into a pair of byte counts.
+func parseSlashPair(s string) (int64, int64) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0
+	}
+	return parseHumanBytes(strings.TrimSpace(parts[0])), parseHumanBytes(strings.TrimSpace(parts[1]))
+}
+
+var humanSizeUnits = map[string]int64{
+	"B":   1,
+	"kB":  1000,
+	"KB":  1000,
+	"MB":  1000 * 1000,
+	"GB":  1000 * 1000 * 1000,
+	"KiB": 1024,
+	"MiB": 1024 * 1024,
+	"GiB": 1024 * 1024 * 1024,
+}
+
+func parseHumanBytes(s string) int64 {
+	i := 0
+	for i < len(s) && (s[i] == '.' || (s[i] >= '0' && s[i] <= '9')) {
+		i++
+	}
+	value, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0
+	}
+	multiplier, ok := humanSizeUnits[s[i:]]
+	if !ok {
+		return 0
+	}
+	return int64(value * float64(multiplier))
+}
+
+func (dm *LocalDockerManager) ContainerTop(ctx context.Context, name string) ([]ProcessInfo, error) {
+	outputBytes, err := exec.CommandContext(ctx, "docker", "top", name).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(outputBytes)))
+	if !scanner.Scan() {
+		return nil, nil
+	}
+	titles := strings.Fields(scanner.Text())
+	if len(titles) == 0 {
+		return nil, nil
+	}
+
+	var processes []ProcessInfo
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < len(titles) {
+			continue
+		}
+
+		// The runtime's last column (COMMAND/CMD) may itself contain
+		// whitespace, so everything from that column on is rejoined.
+		commandIdx := len(titles) - 1
+		process := ProcessInfo{
+			Command: strings.Join(fields[commandIdx:], " "),
+			Extra:   make(map[string]string),
+		}
+		for i, title := range titles[:commandIdx] {
+			switch title {
+			case "PID":
+				process.PID = fields[i]
+			case "UID", "USER":
+				process.User = fields[i]
+			default:
+				process.Extra[title] = fields[i]
+			}
+		}
+
+		processes = append(processes, process)
+	}
+
+	return processes, nil
+}
+
+func (dm *EngineDockerManager) StartContainer(ctx context.Context, name string) error {
+	return dm.cli.ContainerStart(ctx, name, container.StartOptions{})
+}
+
+func (dm *EngineDockerManager) StopContainer(ctx context.Context, name string, timeout time.Duration) error {
+	seconds := int(timeout.Seconds())
+	return dm.cli.ContainerStop(ctx, name, container.StopOptions{Timeout: &seconds})
+}
+
+func (dm *EngineDockerManager) KillContainer(ctx context.Context, name string, signal string) error {
+	return dm.cli.ContainerKill(ctx, name, signal)
+}
+
+func (dm *EngineDockerManager) RestartContainer(ctx context.Context, name string, timeout time.Duration) error {
+	seconds := int(timeout.Seconds())
+	return dm.cli.ContainerRestart(ctx, name, container.StopOptions{Timeout: &seconds})
+}
+
+func (dm *EngineDockerManager) PauseContainer(ctx context.Context, name string) error {
+	return dm.cli.ContainerPause(ctx, name)
+}
+
+func (dm *EngineDockerManager) UnpauseContainer(ctx context.Context, name string) error {
+	return dm.cli.ContainerUnpause(ctx, name)
+}
+
+func (dm *EngineDockerManager) RemoveContainer(ctx context.Context, name string, opts RemoveOptions) error {
+	return dm.cli.ContainerRemove(ctx, name, container.RemoveOptions{
+		Force:         opts.Force,
+		RemoveVolumes: opts.RemoveVolumes,
+	})
+}
+
+// ContainerLogs returns name's logs as plain bytes, matching the shape the
+// CLI backend's `docker logs` produces. The Engine API only returns raw,
+// undemuxed bytes for containers started with a TTY; otherwise stdout and
+// stderr are multiplexed behind an 8-byte frame header per chunk (see
+// stdcopy.StdCopy's doc comment), so we demux non-TTY containers here -
+// without it, callers would see frame-header garbage interleaved in the
+// output.
+func (dm *EngineDockerManager) ContainerLogs(ctx context.Context, name string, opts LogsOptions) (io.ReadCloser, error) {
+	inspect, err := dm.cli.ContainerInspect(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := dm.cli.ContainerLogs(ctx, name, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Tail:       opts.Tail,
+		Since:      opts.Since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if inspect.Config != nil && inspect.Config.Tty {
+		return raw, nil
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, copyErr := stdcopy.StdCopy(pw, pw, raw)
+		raw.Close()
+		pw.CloseWithError(copyErr)
+	}()
+
+	return pr, nil
+}
+
+func (dm *EngineDockerManager) ContainerStats(ctx context.Context, name string, stream bool) (<-chan ContainerStats, error) {
+	resp, err := dm.cli.ContainerStats(ctx, name, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan ContainerStats)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var raw engineStatsJSON
+			if err := decoder.Decode(&raw); err != nil {
+				if err != io.EOF {
+					dm.logger.Warnf("Failed to decode container stats: %v", err)
+				}
+				return
+			}
+
+			select {
+			case out <- raw.toContainerStats():
+			case <-ctx.Done():
+				return
+			}
+
+			if !stream {
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// engineStatsJSON mirrors the subset of types.StatsJSON needed to compute
+// ContainerStats, decoded by hand so this package doesn't need to track the
+// full upstream schema.
+type engineStatsJSON struct {
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+		OnlineCPUs  uint32 `json:"online_cpus"`
+	} `json:"cpu_stats"`
+	PrecpuStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+	} `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64 `json:"usage"`
+		Limit uint64 `json:"limit"`
+	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+	BlkioStats struct {
+		IoServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+}
+
+func (s engineStatsJSON) toContainerStats() ContainerStats {
+	var cpuPercent float64
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PrecpuStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PrecpuStats.SystemUsage)
+	if systemDelta > 0 && cpuDelta > 0 {
+		cpuPercent = (cpuDelta / systemDelta) * float64(s.CPUStats.OnlineCPUs) * 100
+	}
+
+	var rxBytes, txBytes uint64
+	for _, net := range s.Networks {
+		rxBytes += net.RxBytes
+		txBytes += net.TxBytes
+	}
+
+	var readBytes, writeBytes uint64
+	for _, entry := range s.BlkioStats.IoServiceBytesRecursive {
+		switch entry.Op {
+		case "Read":
+			readBytes += entry.Value
+		case "Write":
+			writeBytes += entry.Value
+		}
+	}
+
+	return ContainerStats{
+		CPUPercent:       cpuPercent,
+		MemoryUsageBytes: int64(s.MemoryStats.Usage),
+		MemoryLimitBytes: int64(s.MemoryStats.Limit),
+		NetworkRxBytes:   int64(rxBytes),
+		NetworkTxBytes:   int64(txBytes),
+		BlockReadBytes:   int64(readBytes),
+		BlockWriteBytes:  int64(writeBytes),
+	}
+}
+
+func (dm *EngineDockerManager) ContainerTop(ctx context.Context, name string) ([]ProcessInfo, error) {
+	top, err := dm.cli.ContainerTop(ctx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(top.Titles) == 0 {
+		return nil, nil
+	}
+
+	var processes []ProcessInfo
+	commandIdx := len(top.Titles) - 1
+	for _, row := range top.Processes {
+		if len(row) < len(top.Titles) {
+			continue
+		}
+
+		process := ProcessInfo{
+			Command: strings.Join(row[commandIdx:], " "),
+			Extra:   make(map[string]string),
+		}
+		for i, title := range top.Titles[:commandIdx] {
+			switch title {
+			case "PID":
+				process.PID = row[i]
+			case "UID", "USER":
+				process.User = row[i]
+			default:
+				process.Extra[title] = row[i]
+			}
+		}
+
+		processes = append(processes, process)
+	}
+
+	return processes, nil
+}
@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHumanBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int64
+	}{
+		{"plain bytes", "512B", 512},
+		{"binary KiB", "10KiB", 10 * 1024},
+		{"binary MiB", "1.5MiB", int64(1.5 * 1024 * 1024)},
+		{"binary GiB", "2GiB", 2 * 1024 * 1024 * 1024},
+		{"decimal kB", "1.2kB", int64(1.2 * 1000)},
+		{"uppercase KB", "10KB", 10 * 1000},
+		{"decimal MB", "48MB", 48 * 1000 * 1000},
+		{"unknown unit", "10XB", 0},
+		{"unparseable amount", "abcB", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseHumanBytes(tt.s))
+		})
+	}
+}
+
+func TestParseSlashPair(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		wantFirst int64
+		wantLast  int64
+	}{
+		{"mem usage", "10MiB / 2GiB", 10 * 1024 * 1024, 2 * 1024 * 1024 * 1024},
+		{"net io", "1.2kB / 3.4kB", int64(1.2 * 1000), int64(3.4 * 1000)},
+		{"no slash", "10MiB", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := parseSlashPair(tt.s)
+			assert.Equal(t, tt.wantFirst, first)
+			assert.Equal(t, tt.wantLast, last)
+		})
+	}
+}
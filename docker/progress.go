@@ -0,0 +1,283 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/docker/docker/api/types/image"
+)
+
+// PullProgress reports the state of a single layer (or an aggregate
+// "Pull complete" event) partway through a PullImageWithProgress call, so a
+// caller can surface something better than silence during a multi-gigabyte
+// pull.
+//
+// On the CLI backend (LocalDockerManager), ProgressPercent stays 0 until
+// the final "Pull complete" event: with stdout piped (non-TTY), `docker
+// pull` only emits per-layer status lines ("Downloading", "Verifying
+// Checksum", "Pull complete"), not the byte counters its TTY progress bar
+// shows, so Current/Total can't be computed. Only the Engine API backend
+// (EngineDockerManager), which decodes the daemon's own JSON progress
+// stream directly, can report a real ProgressPercent mid-pull.
+type PullProgress struct {
+	LayerID         string
+	Status          string
+	Current         int64
+	Total           int64
+	ProgressPercent float64
+}
+
+// layerState tracks the current/total byte counts seen so far for a single
+// layer, so PullImageWithProgress can report progress aggregated across
+// every layer in the pull rather than just the most recently updated one.
+type layerState struct {
+	current int64
+	total   int64
+}
+
+// aggregateProgress folds layers into a single 0-100 percentage across all layers
+// with a known total. Layers with no known total (e.g. "Waiting",
+// "Already exists") are ignored rather than dragging the average down.
+func aggregateProgress(layers map[string]layerState) float64 {
+	var current, total int64
+	for _, layer := range layers {
+		if layer.total == 0 {
+			continue
+		}
+		current += layer.current
+		total += layer.total
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(current) / float64(total) * 100
+}
+
+// dockerPullMessage mirrors the newline-delimited JSON objects the Engine
+// API's ImagePull stream emits, one per status update.
+type dockerPullMessage struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	// Error and ErrorDetail carry a failed pull (bad digest, "manifest
+	// unknown", auth denied, ...): the Engine API's ImagePull only errors on
+	// a failed *request*, so a pull that fails after the stream has already
+	// started shows up as one of these messages, not as a Go error. Every
+	// reader of this stream must check them - see jsonmessage.JSONMessage's
+	// own Error field, which this mirrors.
+	Error       string `json:"error"`
+	ErrorDetail struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+}
+
+// scanPullStream reads newline-delimited JSON pull progress messages from r,
+// maintaining per-layer totals in layers and invoking onProgress for every
+// message plus a final synthetic "Pull complete" aggregate event. It returns
+// an error if the stream itself fails to read, or if the daemon reports the
+// pull failed via an {"error": ...} message.
+func scanPullStream(r io.Reader, onProgress func(PullProgress)) error {
+	layers := make(map[string]layerState)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg dockerPullMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			// Skip any line that isn't a JSON object rather than failing
+			// the whole pull.
+			continue
+		}
+
+		if msg.Error != "" {
+			return fmt.Errorf("pulling image: %s", msg.Error)
+		}
+
+		if msg.ID != "" {
+			state := layers[msg.ID]
+			if msg.ProgressDetail.Total > 0 {
+				state.current = msg.ProgressDetail.Current
+				state.total = msg.ProgressDetail.Total
+			}
+			layers[msg.ID] = state
+		}
+
+		if onProgress != nil {
+			onProgress(PullProgress{
+				LayerID:         msg.ID,
+				Status:          msg.Status,
+				Current:         msg.ProgressDetail.Current,
+				Total:           msg.ProgressDetail.Total,
+				ProgressPercent: aggregateProgress(layers),
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if onProgress != nil {
+		onProgress(PullProgress{
+			Status:          "Pull complete",
+			ProgressPercent: aggregateProgress(layers),
+		})
+	}
+
+	return nil
+}
+
+var layerIDPattern = regexp.MustCompile(`^([0-9a-f]{12}):\s*(.*)$`)
+
+// scanPlainPullStream parses the human-readable `docker pull` output the CLI
+// backend gets: one line per layer status change, e.g.
+// "a1b2c3d4e5f6: Downloading" or, with a TTY attached, the progress-bar form
+// "a1b2c3d4e5f6: Downloading [===>  ] 12MB/48MB". With stdout piped to us
+// (never a TTY), the docker CLI always emits the former - the byte-counter
+// suffix byteProgressPattern looks for only appears in the TTY progress-bar
+// rendering, so ProgressPercent stays 0 for every event here except the
+// final synthetic "Pull complete" one. Status is still reported per layer,
+// so callers that only need "which layer is doing what" are unaffected;
+// callers that need a running pull_percent mid-pull must use the Engine API
+// backend instead.
+func scanPlainPullStream(r io.Reader, onProgress func(PullProgress)) error {
+	layers := make(map[string]layerState)
+	byteProgressPattern := regexp.MustCompile(`^([0-9.]+)([a-zA-Z]+)/([0-9.]+)([a-zA-Z]+)$`)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		matches := layerIDPattern.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		layerID, status := matches[1], matches[2]
+
+		var current, total int64
+		fields := byteProgressPattern.FindStringSubmatch(lastField(status))
+		if fields != nil {
+			current = parseSize(fields[1], fields[2])
+			total = parseSize(fields[3], fields[4])
+		}
+
+		state := layers[layerID]
+		if total > 0 {
+			state.current = current
+			state.total = total
+		}
+		layers[layerID] = state
+
+		if onProgress != nil {
+			onProgress(PullProgress{
+				LayerID:         layerID,
+				Status:          status,
+				Current:         current,
+				Total:           total,
+				ProgressPercent: aggregateProgress(layers),
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if onProgress != nil {
+		onProgress(PullProgress{
+			Status:          "Pull complete",
+			ProgressPercent: 100,
+		})
+	}
+
+	return nil
+}
+
+// lastField returns the final whitespace-separated token of s, which for a
+// `docker pull` progress line is the "current/total" byte counter, e.g.
+// "Downloading [===>  ] 12MB/48MB" -> "12MB/48MB".
+func lastField(s string) string {
+	fields := []rune(s)
+	end := len(fields)
+	start := end
+	for start > 0 && fields[start-1] != ' ' {
+		start--
+	}
+	return string(fields[start:end])
+}
+
+var sizeUnits = map[string]int64{
+	"B":  1,
+	"KB": 1000,
+	"MB": 1000 * 1000,
+	"GB": 1000 * 1000 * 1000,
+}
+
+func parseSize(amount string, unit string) int64 {
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0
+	}
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0
+	}
+	return int64(value * float64(multiplier))
+}
+
+// PullImageWithProgress pulls name@repoDigest, invoking onProgress for every
+// layer status update so a caller can report e.g. pull_percent while the
+// pull is in-flight instead of only finding out once it's already done.
+//
+// Because the CLI backend pipes `docker pull`'s stdout (never a TTY), the
+// per-layer byte counters the interactive progress bar shows aren't part of
+// the output - see scanPlainPullStream. ProgressPercent will therefore read
+// 0 throughout the pull and only reflect 100 on the final event; Status
+// still updates per layer ("Waiting", "Downloading", "Pull complete", ...).
+// Use EngineDockerManager.PullImageWithProgress for a real mid-pull percent.
+func (dm *LocalDockerManager) PullImageWithProgress(ctx context.Context, name string, repoDigest string, onProgress func(PullProgress)) error {
+	ref := fmt.Sprintf("%s@%s", name, repoDigest)
+	dm.logger.Debugf("Pulling image %s with progress", ref)
+
+	proc := exec.CommandContext(ctx, "docker", "pull", ref)
+	stdout, err := proc.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	proc.Stderr = proc.Stdout
+
+	if err := proc.Start(); err != nil {
+		return err
+	}
+
+	if err := scanPlainPullStream(stdout, onProgress); err != nil {
+		dm.logger.Errorf("Failed to parse pull progress for %s: %v", ref, err)
+	}
+
+	return proc.Wait()
+}
+
+func (dm *EngineDockerManager) PullImageWithProgress(ctx context.Context, name string, repoDigest string, onProgress func(PullProgress)) error {
+	ref := fmt.Sprintf("%s@%s", name, repoDigest)
+	dm.logger.Debugf("Pulling image %s with progress", ref)
+
+	reader, err := dm.cli.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return scanPullStream(reader, onProgress)
+}
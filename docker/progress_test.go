@@ -0,0 +1,30 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount string
+		unit   string
+		want   int64
+	}{
+		{"bytes", "512", "B", 512},
+		{"kilobytes", "12", "KB", 12 * 1000},
+		{"megabytes", "48", "MB", 48 * 1000 * 1000},
+		{"gigabytes", "2", "GB", 2 * 1000 * 1000 * 1000},
+		{"fractional megabytes", "1.5", "MB", int64(1.5 * 1000 * 1000)},
+		{"unknown unit", "10", "KiB", 0},
+		{"unparseable amount", "abc", "MB", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseSize(tt.amount, tt.unit))
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+)
+
+// RegistryAuth is the set of credentials needed to authenticate an image
+// pull against one registry, mirroring the fields the Engine API's
+// X-Registry-Auth header and `docker login` both expect.
+type RegistryAuth struct {
+	ServerAddress string
+	Username      string
+	Password      string
+	IdentityToken string
+	RegistryToken string
+}
+
+// RegistryAuthProvider resolves the credentials for one registry (or one
+// image reference, for providers that need to inspect it, e.g. to pick a
+// region-specific ECR endpoint).
+type RegistryAuthProvider interface {
+	Resolve(ctx context.Context, imageRef string) (RegistryAuth, error)
+}
+
+// CredentialStore picks a RegistryAuthProvider based on the registry host
+// embedded in an image reference, so PullPrivateImage-style flows aren't
+// hard-wired to a single registry.
+type CredentialStore struct {
+	providers map[string]RegistryAuthProvider
+	fallback  RegistryAuthProvider
+}
+
+// NewCredentialStore returns an empty CredentialStore; register providers
+// with RegisterHost/SetFallback before resolving against it.
+func NewCredentialStore() *CredentialStore {
+	return &CredentialStore{providers: make(map[string]RegistryAuthProvider)}
+}
+
+// RegisterHost maps a registry host (e.g. "ghcr.io", "123456789012.dkr.ecr.us-east-1.amazonaws.com")
+// to the provider that should authenticate pulls against it.
+func (s *CredentialStore) RegisterHost(host string, provider RegistryAuthProvider) {
+	s.providers[host] = provider
+}
+
+// SetFallback sets the provider consulted when an image's registry host has
+// no provider registered via RegisterHost, e.g. a Docker-credential-helper
+// shim that covers "whatever's in ~/.docker/config.json".
+func (s *CredentialStore) SetFallback(provider RegistryAuthProvider) {
+	s.fallback = provider
+}
+
+// Resolve returns the credentials to use for imageRef, consulting the
+// provider registered for its registry host, then the fallback provider.
+func (s *CredentialStore) Resolve(ctx context.Context, imageRef string) (RegistryAuth, error) {
+	host := registryHost(imageRef)
+	if provider, ok := s.providers[host]; ok {
+		return provider.Resolve(ctx, imageRef)
+	}
+	if s.fallback != nil {
+		return s.fallback.Resolve(ctx, imageRef)
+	}
+	return RegistryAuth{}, fmt.Errorf("no registry credentials configured for %s", host)
+}
+
+// registryHost extracts the registry host from an image reference, matching
+// Docker's own rule: the part before the first '/' only counts as a host if
+// it contains a '.', a ':', or is literally "localhost"; otherwise the image
+// is assumed to live on Docker Hub.
+func registryHost(imageRef string) string {
+	name := imageRef
+	if at := strings.Index(name, "@"); at != -1 {
+		name = name[:at]
+	}
+
+	firstSlash := strings.Index(name, "/")
+	if firstSlash == -1 {
+		return "registry-1.docker.io"
+	}
+
+	candidate := name[:firstSlash]
+	if candidate == "localhost" || strings.ContainsAny(candidate, ".:") {
+		return candidate
+	}
+	return "registry-1.docker.io"
+}
+
+// StaticCredentialProvider returns the same fixed credentials for every
+// image, for the common case of one username/password pair per registry.
+type StaticCredentialProvider struct {
+	Auth RegistryAuth
+}
+
+func (p StaticCredentialProvider) Resolve(ctx context.Context, imageRef string) (RegistryAuth, error) {
+	return p.Auth, nil
+}
+
+// GHCRPATProvider authenticates against ghcr.io with a GitHub username and
+// personal access token, i.e. PullPrivateImage's original, GHCR-only
+// behavior, now expressed as one of several possible providers.
+type GHCRPATProvider struct {
+	GitHubUsername string
+	PAT            string
+}
+
+func (p GHCRPATProvider) Resolve(ctx context.Context, imageRef string) (RegistryAuth, error) {
+	return RegistryAuth{
+		ServerAddress: "ghcr.io",
+		Username:      p.GitHubUsername,
+		Password:      p.PAT,
+	}, nil
+}
+
+// DockerHubProvider authenticates against Docker Hub with a username and
+// password or access token.
+type DockerHubProvider struct {
+	Username string
+	Password string
+}
+
+func (p DockerHubProvider) Resolve(ctx context.Context, imageRef string) (RegistryAuth, error) {
+	return RegistryAuth{
+		ServerAddress: "registry-1.docker.io",
+		Username:      p.Username,
+		Password:      p.Password,
+	}, nil
+}
+
+// ECRProvider authenticates against an AWS ECR repository by exchanging AWS
+// credentials for a short-lived registry token via ecr:GetAuthorizationToken.
+type ECRProvider struct {
+	Client *ecr.Client
+}
+
+func (p *ECRProvider) Resolve(ctx context.Context, imageRef string) (RegistryAuth, error) {
+	out, err := p.Client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return RegistryAuth{}, fmt.Errorf("ecr:GetAuthorizationToken: %w", err)
+	}
+	if len(out.AuthorizationData) == 0 {
+		return RegistryAuth{}, fmt.Errorf("ecr:GetAuthorizationToken returned no authorization data")
+	}
+
+	data := out.AuthorizationData[0]
+	decoded, err := base64.StdEncoding.DecodeString(aws.ToString(data.AuthorizationToken))
+	if err != nil {
+		return RegistryAuth{}, fmt.Errorf("decoding ECR authorization token: %w", err)
+	}
+
+	userPass := strings.SplitN(string(decoded), ":", 2)
+	if len(userPass) != 2 {
+		return RegistryAuth{}, fmt.Errorf("unexpected ECR authorization token format")
+	}
+
+	return RegistryAuth{
+		ServerAddress: strings.TrimPrefix(aws.ToString(data.ProxyEndpoint), "https://"),
+		Username:      userPass[0],
+		Password:      userPass[1],
+	}, nil
+}
+
+// GCPArtifactRegistryProvider authenticates against a GCP Artifact Registry
+// host using the access token for whatever identity `gcloud`/workload
+// identity is configured for on this machine.
+type GCPArtifactRegistryProvider struct {
+	// Host is the Artifact Registry host this provider serves, e.g.
+	// "us-docker.pkg.dev".
+	Host string
+}
+
+func (p GCPArtifactRegistryProvider) Resolve(ctx context.Context, imageRef string) (RegistryAuth, error) {
+	out, err := exec.CommandContext(ctx, "gcloud", "auth", "print-access-token").Output()
+	if err != nil {
+		return RegistryAuth{}, fmt.Errorf("gcloud auth print-access-token: %w", err)
+	}
+
+	return RegistryAuth{
+		ServerAddress: p.Host,
+		Username:      "oauth2accesstoken",
+		Password:      strings.TrimSpace(string(out)),
+	}, nil
+}
+
+// DockerCredentialHelperProvider authenticates using whatever
+// docker-credential-* helper ~/.docker/config.json delegates to for a given
+// host, the same mechanism the docker CLI itself uses for `docker login`.
+type DockerCredentialHelperProvider struct {
+	// ConfigPath overrides the default ~/.docker/config.json, mainly for
+	// tests.
+	ConfigPath string
+}
+
+func (p DockerCredentialHelperProvider) Resolve(ctx context.Context, imageRef string) (RegistryAuth, error) {
+	host := registryHost(imageRef)
+
+	helper, err := p.helperFor(host)
+	if err != nil {
+		return RegistryAuth{}, err
+	}
+
+	cmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(host)
+	out, err := cmd.Output()
+	if err != nil {
+		return RegistryAuth{}, fmt.Errorf("docker-credential-%s get: %w", helper, err)
+	}
+
+	var creds struct {
+		ServerURL string `json:"ServerURL"`
+		Username  string `json:"Username"`
+		Secret    string `json:"Secret"`
+	}
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return RegistryAuth{}, err
+	}
+
+	return RegistryAuth{
+		ServerAddress: creds.ServerURL,
+		Username:      creds.Username,
+		Password:      creds.Secret,
+	}, nil
+}
+
+func (p DockerCredentialHelperProvider) helperFor(host string) (string, error) {
+	path := p.ConfigPath
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, ".docker", "config.json")
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var cfg struct {
+		CredHelpers map[string]string `json:"credHelpers"`
+		CredsStore  string            `json:"credsStore"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return "", err
+	}
+
+	if helper, ok := cfg.CredHelpers[host]; ok {
+		return helper, nil
+	}
+	if cfg.CredsStore != "" {
+		return cfg.CredsStore, nil
+	}
+	return "", fmt.Errorf("no credential helper configured for %s", host)
+}
+
+// PullImageFromRegistry pulls imageRef, resolving credentials for it from
+// credentials (which may route Docker Hub, GHCR, ECR, GCR, or any other
+// registry to the right provider). This generalizes PullPrivateImage, which
+// is hard-wired to ghcr.io.
+func (dm *EngineDockerManager) PullImageFromRegistry(ctx context.Context, imageRef string, credentials *CredentialStore) error {
+	auth, err := credentials.Resolve(ctx, imageRef)
+	if err != nil {
+		return err
+	}
+
+	encodedAuth, err := json.Marshal(registry.AuthConfig{
+		ServerAddress: auth.ServerAddress,
+		Username:      auth.Username,
+		Password:      auth.Password,
+		IdentityToken: auth.IdentityToken,
+		RegistryToken: auth.RegistryToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	dm.logger.Debugf("Pulling %s from %s", imageRef, auth.ServerAddress)
+	reader, err := dm.cli.ImagePull(ctx, imageRef, image.PullOptions{
+		RegistryAuth: base64.URLEncoding.EncodeToString(encodedAuth),
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// ImagePull only errors on a failed *request* - an actual pull failure
+	// (bad digest, "manifest unknown", auth denied) arrives as a message in
+	// the stream body itself, so it must be decoded rather than discarded.
+	if err := scanPullStream(reader, nil); err != nil {
+		return fmt.Errorf("pulling %s: %w", imageRef, err)
+	}
+	return nil
+}
+
+// PullImageFromRegistry pulls imageRef by logging in to its resolved
+// registry host with `docker login --password-stdin`, then pulling as
+// PullPrivateImage already does for the GHCR-only case.
+func (dm *LocalDockerManager) PullImageFromRegistry(ctx context.Context, imageRef string, credentials *CredentialStore) error {
+	auth, err := credentials.Resolve(ctx, imageRef)
+	if err != nil {
+		return err
+	}
+
+	loginCmd := exec.CommandContext(ctx, "docker", "login", auth.ServerAddress, "-u", auth.Username, "--password-stdin")
+	loginCmd.Stdin = strings.NewReader(auth.Password)
+	if out, err := loginCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("docker login %s: %w (output: %s)", auth.ServerAddress, err, out)
+	}
+
+	dm.logger.Debugf("Pulling %s from %s", imageRef, auth.ServerAddress)
+	pullCmd := exec.CommandContext(ctx, "docker", "pull", imageRef)
+	out, err := pullCmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker pull %s: %w (output: %s)", imageRef, err, out)
+	}
+
+	dm.logger.Debugf("Output: %s", string(out))
+	return nil
+}
@@ -0,0 +1,30 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		imageRef string
+		want     string
+	}{
+		{"docker hub short name", "ubuntu", "registry-1.docker.io"},
+		{"docker hub namespaced", "library/ubuntu", "registry-1.docker.io"},
+		{"docker hub with tag", "ubuntu:22.04", "registry-1.docker.io"},
+		{"ghcr host", "ghcr.io/owner/repo:latest", "ghcr.io"},
+		{"host with port", "localhost:5000/myimage", "localhost:5000"},
+		{"registry host with port", "registry.example.com:5000/myimage", "registry.example.com:5000"},
+		{"bare localhost", "localhost/myimage", "localhost"},
+		{"digest stripped before host check", "ghcr.io/owner/repo@sha256:abc", "ghcr.io"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, registryHost(tt.imageRef))
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"go.viam.com/rdk/logging"
+)
+
+// ContainerRuntime selects which concrete container engine a DockerManager
+// talks to. Many edge/Viam deployments (RHEL, Fedora IoT, rootless
+// Raspberry Pi installs) ship Podman rather than Docker.
+type ContainerRuntime string
+
+const (
+	RuntimeDocker ContainerRuntime = "docker"
+	RuntimePodman ContainerRuntime = "podman"
+	// RuntimeAuto picks Docker or Podman based on which daemon socket
+	// exists on this host, preferring Docker when both are present.
+	RuntimeAuto ContainerRuntime = "auto"
+)
+
+const dockerSocketPath = "/var/run/docker.sock"
+
+// podmanSocketPath returns the per-user libpod API socket Podman listens
+// on, following $XDG_RUNTIME_DIR the same way podman itself does.
+func podmanSocketPath() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+	return filepath.Join(dir, "podman", "podman.sock")
+}
+
+// NewRuntimeManager returns a DockerManager targeting runtime. RuntimeAuto
+// detects Docker vs. Podman by checking which daemon socket exists.
+func NewRuntimeManager(logger logging.Logger, runtime ContainerRuntime) (DockerManager, error) {
+	switch runtime {
+	case RuntimeDocker:
+		return NewEngineDockerManager(logger)
+	case RuntimePodman:
+		return NewPodmanManager(logger)
+	case RuntimeAuto, "":
+		if _, err := os.Stat(dockerSocketPath); err == nil {
+			return NewEngineDockerManager(logger)
+		}
+		if _, err := os.Stat(podmanSocketPath()); err == nil {
+			return NewPodmanManager(logger)
+		}
+		return nil, fmt.Errorf("no docker or podman socket found (checked %s and %s)", dockerSocketPath, podmanSocketPath())
+	default:
+		return nil, fmt.Errorf("unknown container runtime %q", runtime)
+	}
+}
+
+// PodmanManager implements DockerManager against Podman's libpod API
+// socket. Podman's Docker-compatible endpoints (used for everything but
+// image resolution) are close enough to the real thing that PodmanManager
+// embeds an EngineDockerManager pointed at the podman socket and only
+// overrides the pieces where Podman's semantics genuinely differ.
+type PodmanManager struct {
+	*EngineDockerManager
+	socketPath string
+}
+
+// NewPodmanManager builds a client for the current user's podman.sock. It
+// does not connect to the socket; that happens on the first request.
+func NewPodmanManager(logger logging.Logger) (*PodmanManager, error) {
+	socketPath := podmanSocketPath()
+
+	cli, err := client.NewClientWithOpts(
+		client.WithHost("unix://"+socketPath),
+		client.WithAPIVersionNegotiation(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PodmanManager{
+		EngineDockerManager: &EngineDockerManager{logger: logger, cli: cli},
+		socketPath:          socketPath,
+	}, nil
+}
+
+// ListImages fills in RepoDigest from Podman's native libpod endpoint for
+// any image the Docker-compatible endpoint reported as "<none>", using the
+// libpod summary's Digest field or, failing that, its first RepoDigests
+// entry; the compat API doesn't always surface a digest for images that
+// were only ever pulled by a short name.
+func (dm *PodmanManager) ListImages(ctx context.Context) ([]DockerImageDetails, error) {
+	images, err := dm.EngineDockerManager.ListImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	digests, err := dm.libpodRepoDigests(ctx)
+	if err != nil {
+		dm.logger.Warnf("Failed to query libpod image digests, RepoDigest may be incomplete: %v", err)
+		return images, nil
+	}
+
+	for i, img := range images {
+		if img.RepoDigest == "<none>" {
+			if digest, ok := digests[img.ImageID]; ok {
+				images[i].RepoDigest = digest
+			}
+		}
+	}
+
+	return images, nil
+}
+
+// libpodImageSummary is the subset of libpod's /libpod/images/json response
+// ListImages needs.
+type libpodImageSummary struct {
+	ID           string   `json:"Id"`
+	Digest       string   `json:"Digest"`
+	RepoDigests  []string `json:"RepoDigests"`
+	NamesHistory []string `json:"NamesHistory"`
+}
+
+func (dm *PodmanManager) libpodRepoDigests(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://podman/v4.0.0/libpod/images/json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := dm.libpodHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var summaries []libpodImageSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summaries); err != nil {
+		return nil, err
+	}
+
+	digests := make(map[string]string, len(summaries))
+	for _, summary := range summaries {
+		digest := summary.Digest
+		if digest == "" && len(summary.RepoDigests) > 0 {
+			digest = repoDigestOnly(summary.RepoDigests[0])
+		}
+		if digest != "" {
+			digests[summary.ID] = digest
+		}
+	}
+
+	return digests, nil
+}
+
+// libpodHTTPClient dials the libpod socket directly; the host in the
+// request URL above ("podman") is ignored beyond request construction,
+// exactly as it is for the Engine API client's own unix-socket transport.
+func (dm *PodmanManager) libpodHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", dm.socketPath)
+			},
+		},
+	}
+}
+
+// PullImage pulls name@repoDigest, first checking whether the image
+// ResolveImageName returns for name (the first one matching on a
+// repository-path boundary) already has that digest; other matching images
+// are not checked. Podman's short-name resolution (registries.conf)
+// can leave an image registered locally under a repository that doesn't
+// look like name itself (e.g. pulled as "docker.io/library/ubuntu" but
+// referenced as "ubuntu"), so the exact-match check ListImages alone would
+// do isn't enough to avoid a redundant pull.
+func (dm *PodmanManager) PullImage(ctx context.Context, name string, repoDigest string) error {
+	if existing, err := dm.ResolveImageName(ctx, name); err == nil && existing.RepoDigest == repoDigest {
+		dm.logger.Debugf("Image %s already present with digest %s, skipping pull", name, repoDigest)
+		return nil
+	}
+	return dm.EngineDockerManager.PullImage(ctx, name, repoDigest)
+}
+
+// ResolveImageName finds the image whose repository matches name on a
+// repository-path boundary, the short-name resolution semantics Podman's
+// registries.conf implies (so "foo" matches "registry/ns/foo" but not
+// "myfoo") that Docker's bare string compare doesn't need, since Docker
+// requires short names to already be fully-qualified locally.
+//
+// Note that a tag literally named "none" (e.g. "foo:none") is a real,
+// resolvable tag here - it is not the "<none>" sentinel ListImages uses for
+// an image with no tag at all, and must not be treated as untagged.
+func (dm *PodmanManager) ResolveImageName(ctx context.Context, name string) (DockerImageDetails, error) {
+	images, err := dm.ListImages(ctx)
+	if err != nil {
+		return DockerImageDetails{}, err
+	}
+
+	repo, tag := splitRepoTag(name)
+	for _, img := range images {
+		if !repositoryMatchesBoundary(img.Repository, repo) {
+			continue
+		}
+		if tag == "" || img.Tag == tag {
+			return img, nil
+		}
+	}
+
+	return DockerImageDetails{}, ErrImageDoesNotExist
+}
+
+// repositoryMatchesBoundary reports whether repository resolves short name
+// name on a full path-segment boundary: "foo" matches "registry/ns/foo" and
+// "foo" itself, but not "myfoo" or "foobar".
+func repositoryMatchesBoundary(repository, name string) bool {
+	if repository == name {
+		return true
+	}
+	return strings.HasSuffix(repository, "/"+name)
+}
+
+var _ DockerManager = (*PodmanManager)(nil)
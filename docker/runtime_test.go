@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryMatchesBoundary(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		short      string
+		want       bool
+	}{
+		{"exact match", "foo", "foo", true},
+		{"path boundary match", "registry/ns/foo", "foo", true},
+		{"prefix without boundary", "myfoo", "foo", false},
+		{"suffix without boundary", "foobar", "foo", false},
+		{"nested path boundary match", "docker.io/library/foo", "foo", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, repositoryMatchesBoundary(tt.repository, tt.short))
+		})
+	}
+}
+
+func TestSplitRepoTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoTag  string
+		wantRepo string
+		wantTag  string
+	}{
+		{"repo and tag", "ubuntu:22.04", "ubuntu", "22.04"},
+		{"no tag", "ubuntu", "ubuntu", ""},
+		{"namespaced repo and tag", "registry/ns/foo:latest", "registry/ns/foo", "latest"},
+		{"digest ref", "ubuntu@sha256:abc123", "ubuntu", ""},
+		{"namespaced digest ref", "registry/ns/foo@sha256:abc123", "registry/ns/foo", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, tag := splitRepoTag(tt.repoTag)
+			assert.Equal(t, tt.wantRepo, repo)
+			assert.Equal(t, tt.wantTag, tag)
+		})
+	}
+}
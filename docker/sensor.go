@@ -0,0 +1,345 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// Model identifies the docker-manager sensor model to the Viam resource
+// registry. It brings up one compose project, tracking ImageName@RepoDigest
+// as the version to run and tearing the previous one down whenever
+// Reconfigure points it at a different digest.
+var Model = resource.NewModel("viam-labs", "docker", "docker-manager")
+
+func init() {
+	resource.RegisterComponent(
+		sensor.API,
+		Model,
+		resource.Registration[sensor.Sensor, *Config]{
+			Constructor: NewDockerSensor,
+		},
+	)
+}
+
+// Config is the docker-manager model's attributes schema.
+type Config struct {
+	// ImageName and RepoDigest pin the service image a reconfigure compares
+	// against to decide whether the compose project needs to come down and
+	// back up.
+	ImageName  string `json:"image_name"`
+	RepoDigest string `json:"repo_digest"`
+	// ComposeFile is an inline compose document, given as one string per
+	// line so it round-trips cleanly through Viam app config JSON;
+	// writeComposeFile joins it back with newlines before LoadProject
+	// parses it.
+	ComposeFile []string `json:"compose_file,omitempty"`
+	// Registries configures static credentials for the private registry
+	// ImageName is pulled from. ComposeManager.Up pulls ComposeFile's
+	// service images without these credentials. See registry.go's
+	// CredentialStore for the other provider types (ECR, GCP Artifact
+	// Registry, ...) this doesn't cover.
+	Registries []RegistryConfig `json:"registries,omitempty"`
+	// Runtime selects which container engine to manage: "docker", "podman",
+	// or "auto" (the default) to detect based on which socket exists on
+	// this host. See runtime.go's NewRuntimeManager.
+	Runtime ContainerRuntime `json:"runtime,omitempty"`
+}
+
+// RegistryConfig is one entry of Config.Registries: static credentials for
+// a single registry host.
+type RegistryConfig struct {
+	Host     string `json:"host"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Validate parses ComposeFile (when set) so a malformed compose document -
+// bad indentation, an unknown key, a service missing image: - is caught at
+// config-validate time instead of only once Reconfigure tries to bring it
+// up.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.ImageName == "" {
+		return nil, fmt.Errorf("%s: image_name is required", path)
+	}
+	if cfg.RepoDigest == "" {
+		return nil, fmt.Errorf("%s: repo_digest is required", path)
+	}
+
+	if len(cfg.ComposeFile) > 0 {
+		file, err := writeComposeFile(cfg.ComposeFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		defer os.Remove(file)
+
+		var cm ComposeManager
+		if _, err := cm.LoadProject(context.Background(), []string{file}, nil); err != nil {
+			return nil, fmt.Errorf("%s: invalid compose_file: %w", path, err)
+		}
+	}
+
+	return nil, nil
+}
+
+// writeComposeFile joins lines with newlines and writes them to a temp
+// file, since compose-go (and docker compose itself) only parses compose
+// documents from disk.
+func writeComposeFile(lines []string) (string, error) {
+	f, err := os.CreateTemp("", "docker-compose-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(strings.Join(lines, "\n")); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// dockerSensor brings up the single compose project described by its
+// Config, tearing the previous project down and pulling the new image
+// whenever Reconfigure points it at a different ImageName/RepoDigest.
+type dockerSensor struct {
+	resource.Named
+
+	logger  logging.Logger
+	engine  *EngineDockerManager
+	compose *ComposeManager
+
+	mu sync.Mutex
+	// readMu guards pullProgress, and project together with mu, so Readings
+	// and recordPullProgress never wait on mu, which Reconfigure holds
+	// across a whole pull.
+	readMu       sync.Mutex
+	cfg          Config
+	project      *types.Project
+	pullProgress PullProgress
+}
+
+// NewDockerSensor brings up the compose project described by conf and
+// returns a sensor tracking it.
+func NewDockerSensor(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (sensor.Sensor, error) {
+	cfg, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := runtimeEngine(logger, cfg.Runtime)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to container runtime: %w", err)
+	}
+
+	s := &dockerSensor{
+		Named:   conf.ResourceName().AsNamed(),
+		logger:  logger,
+		engine:  engine,
+		compose: NewComposeManager(logger, engine),
+	}
+
+	if err := s.bringUp(ctx, *cfg); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// runtimeEngine resolves runtime (defaulting to RuntimeAuto) to the Engine
+// API client ComposeManager needs. NewRuntimeManager returns either an
+// *EngineDockerManager (RuntimeDocker) or a *PodmanManager (RuntimePodman),
+// which embeds one pointed at the podman socket instead - ComposeManager's
+// NetworkCreate/VolumeCreate/ContainerCreate calls work unchanged against
+// either, since Podman's compat API implements them.
+func runtimeEngine(logger logging.Logger, runtime ContainerRuntime) (*EngineDockerManager, error) {
+	if runtime == "" {
+		runtime = RuntimeAuto
+	}
+
+	dm, err := NewRuntimeManager(logger, runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m := dm.(type) {
+	case *EngineDockerManager:
+		return m, nil
+	case *PodmanManager:
+		return m.EngineDockerManager, nil
+	default:
+		return nil, fmt.Errorf("unsupported docker manager backend %T", dm)
+	}
+}
+
+// bringUp pulls cfg.ImageName@cfg.RepoDigest (tracking its progress for
+// Readings' pull_percent), then parses cfg.ComposeFile and brings its
+// project up, recording cfg and the resulting project on success.
+func (s *dockerSensor) bringUp(ctx context.Context, cfg Config) error {
+	if err := s.pullPinnedImage(ctx, cfg); err != nil {
+		return err
+	}
+
+	file, err := writeComposeFile(cfg.ComposeFile)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file)
+
+	project, err := s.compose.LoadProject(ctx, []string{file}, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.compose.Up(ctx, project, UpOptions{Detach: true}); err != nil {
+		return err
+	}
+
+	s.cfg = cfg
+	s.readMu.Lock()
+	s.project = project
+	s.readMu.Unlock()
+	return nil
+}
+
+// pullPinnedImage pulls cfg.ImageName@cfg.RepoDigest, tracking its progress
+// for Readings' pull_percent/pull_status. When cfg.Registries configures
+// credentials, it pulls via PullImageFromRegistry instead, since that's the
+// only pull path that authenticates against a private registry.
+func (s *dockerSensor) pullPinnedImage(ctx context.Context, cfg Config) error {
+	ref := fmt.Sprintf("%s@%s", cfg.ImageName, cfg.RepoDigest)
+
+	if len(cfg.Registries) == 0 {
+		if err := s.engine.PullImageWithProgress(ctx, cfg.ImageName, cfg.RepoDigest, s.recordPullProgress); err != nil {
+			return fmt.Errorf("pulling %s: %w", ref, err)
+		}
+		return nil
+	}
+
+	credentials := NewCredentialStore()
+	for _, r := range cfg.Registries {
+		credentials.RegisterHost(r.Host, StaticCredentialProvider{Auth: RegistryAuth{
+			ServerAddress: r.Host,
+			Username:      r.Username,
+			Password:      r.Password,
+		}})
+	}
+
+	s.recordPullProgress(PullProgress{Status: "Pulling"})
+	if err := s.engine.PullImageFromRegistry(ctx, ref, credentials); err != nil {
+		return fmt.Errorf("pulling %s: %w", ref, err)
+	}
+	s.recordPullProgress(PullProgress{Status: "Pull complete", ProgressPercent: 100})
+	return nil
+}
+
+// Reconfigure tears the current compose project down and brings the new
+// one up whenever conf points at a different ImageName/RepoDigest, removing
+// the old image afterward so stale versions don't accumulate on disk.
+func (s *dockerSensor) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	cfg, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cfg.ImageName == s.cfg.ImageName && cfg.RepoDigest == s.cfg.RepoDigest {
+		return nil
+	}
+
+	oldDigest := s.cfg.RepoDigest
+	if s.project != nil {
+		if err := s.compose.Down(ctx, s.project, DownOptions{}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.bringUp(ctx, *cfg); err != nil {
+		return err
+	}
+
+	if oldDigest != "" && oldDigest != s.cfg.RepoDigest {
+		if err := s.engine.RemoveImageByRepoDigest(ctx, oldDigest); err != nil {
+			s.logger.Warnf("Failed to remove old image %s: %v", oldDigest, err)
+		}
+	}
+
+	return nil
+}
+
+// recordPullProgress is passed to PullImageWithProgress as its onProgress
+// callback, so Readings can report the most recent pull_percent/pull_status
+// while a pull is in-flight instead of only finding out once it's done.
+func (s *dockerSensor) recordPullProgress(p PullProgress) {
+	s.readMu.Lock()
+	defer s.readMu.Unlock()
+	s.pullProgress = p
+}
+
+// Readings reports this sensor's current pull status plus, for each
+// service container the compose project is running, a one-shot CPU/memory
+// sample keyed "<service>_cpu_percent"/"<service>_memory_usage_bytes" so
+// Viam can graph container resource usage without external tooling.
+func (s *dockerSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	s.readMu.Lock()
+	progress := s.pullProgress
+	project := s.project
+	s.readMu.Unlock()
+
+	readings := map[string]interface{}{
+		"pull_percent": progress.ProgressPercent,
+		"pull_status":  progress.Status,
+	}
+
+	if project == nil {
+		return readings, nil
+	}
+
+	statuses, err := s.compose.PS(ctx, project)
+	if err != nil {
+		s.logger.Warnf("Failed to list service containers for stats: %v", err)
+		return readings, nil
+	}
+
+	for _, status := range statuses {
+		statsCh, err := s.engine.ContainerStats(ctx, status.ContainerID, false)
+		if err != nil {
+			s.logger.Warnf("Failed to get stats for service %s: %v", status.Service, err)
+			continue
+		}
+
+		stats, ok := <-statsCh
+		if !ok {
+			continue
+		}
+
+		readings[fmt.Sprintf("%s_cpu_percent", status.Service)] = stats.CPUPercent
+		readings[fmt.Sprintf("%s_memory_usage_bytes", status.Service)] = stats.MemoryUsageBytes
+	}
+
+	return readings, nil
+}
+
+func (s *dockerSensor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("DoCommand not implemented for %s", Model.String())
+}
+
+// Close tears down the compose project this sensor brought up.
+func (s *dockerSensor) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.project == nil {
+		return nil
+	}
+	return s.compose.Down(ctx, s.project, DownOptions{})
+}
@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"context"
+	"errors"
+)
+
+// The functions below implement the parts of DockerManager that are pure
+// derivations of ListImages/ListContainers/InspectContainer. Both
+// LocalDockerManager (CLI) and EngineDockerManager (Engine API) share the
+// exact same logic here, so it lives once against the DockerManager
+// interface instead of being duplicated per backend.
+
+func getImageDetails(ctx context.Context, dm DockerManager, imageId string) (DockerImageDetails, error) {
+	images, err := dm.ListImages(ctx)
+	if err != nil {
+		return DockerImageDetails{}, err
+	}
+
+	for _, image := range images {
+		if image.ImageID == imageId {
+			return image, nil
+		}
+	}
+	return DockerImageDetails{}, errors.New("image not found")
+}
+
+func getContainerImageId(ctx context.Context, dm DockerManager, containerId string) (string, error) {
+	container, err := dm.InspectContainer(ctx, containerId)
+	if err != nil {
+		return "", err
+	}
+
+	return container["Image"].(string), nil
+}
+
+func getContainerImageDigest(ctx context.Context, dm DockerManager, containerId string) (string, error) {
+	imageId, err := dm.GetContainerImageId(ctx, containerId)
+	if err != nil {
+		return "", err
+	}
+
+	image, err := dm.GetImageDetails(ctx, imageId)
+	if err != nil {
+		return "", err
+	}
+
+	return image.RepoDigest, nil
+}
+
+func getContainersRunningImage(ctx context.Context, dm DockerManager, repoDigest string) ([]DockerContainerDetails, error) {
+	containers, err := dm.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var containersRunningImage []DockerContainerDetails
+	for _, container := range containers {
+		digest, err := dm.GetContainerImageDigest(ctx, container.ContainerID)
+		if err != nil {
+			continue
+		}
+		if digest == repoDigest {
+			containersRunningImage = append(containersRunningImage, container)
+		}
+	}
+
+	return containersRunningImage, nil
+}